@@ -0,0 +1,106 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+import "strings"
+
+// ConditionReason is a machine-parseable, CamelCase reason for a
+// VerticalPodAutoscalerCondition, stable across releases so tooling can
+// switch on it instead of pattern-matching the human-readable Message.
+type ConditionReason string
+
+const (
+	// ReasonTargetRefMissing means the VPA has neither a targetRef nor a
+	// (deprecated) label selector to resolve pods from.
+	ReasonTargetRefMissing ConditionReason = "TargetRefMissing"
+	// ReasonTargetRefFetchFailed means the targetRef's selector could not
+	// be fetched; Params["error"] carries the underlying error text.
+	ReasonTargetRefFetchFailed ConditionReason = "TargetRefFetchFailed"
+	// ReasonBothSelectorsDefined means the VPA has both a targetRef and a
+	// legacy label selector; only one is supported at a time.
+	ReasonBothSelectorsDefined ConditionReason = "BothSelectorsDefined"
+	// ReasonLegacySelectorDeprecated means the VPA resolved its pods via
+	// the deprecated label selector path.
+	ReasonLegacySelectorDeprecated ConditionReason = "LegacySelectorDeprecated"
+	// ReasonTargetRefHasParent means the VPA's targetRef does not point
+	// at a top-level controller.
+	ReasonTargetRefHasParent ConditionReason = "TargetRefHasParent"
+	// ReasonTopLevelLookupError means resolving the targetRef's top-level
+	// controller failed; Params["error"] carries the underlying error
+	// text.
+	ReasonTopLevelLookupError ConditionReason = "TopLevelLookupError"
+	// ReasonTargetAPIVersionDeprecated means the targetRef's resolved
+	// apiVersion/kind is deprecated but still served by the connected
+	// cluster's server version. Params carries "apiVersion", "kind",
+	// "deprecatedInVersion" and "clusterVersion".
+	ReasonTargetAPIVersionDeprecated ConditionReason = "TargetAPIVersionDeprecated"
+	// ReasonTargetKindRemoved means the targetRef's resolved apiVersion/kind
+	// has been removed from the connected cluster's server version, and is
+	// no longer reachable. Params carries "apiVersion", "kind",
+	// "removedInVersion" and "clusterVersion".
+	ReasonTargetKindRemoved ConditionReason = "TargetKindRemoved"
+	// ReasonTargetControllerDeprecated means the targetRef's resolved
+	// apiVersion/kind is deprecated with no known removal version, so the
+	// warning can't be tied to a specific cluster version. Params carries
+	// "apiVersion" and "kind".
+	ReasonTargetControllerDeprecated ConditionReason = "TargetControllerDeprecated"
+	// ReasonControllerUpgradeAvailable means a newer version of the
+	// targetRef's resolved controller is reachable. Params["versions"]
+	// carries a comma-separated "version (reason)" list.
+	ReasonControllerUpgradeAvailable ConditionReason = "ControllerUpgradeAvailable"
+)
+
+// conditionMessageTemplates maps each ConditionReason to the template used
+// to render its human-readable Message. Placeholders are written as
+// "{{name}}" and substituted from the condition's Params.
+var conditionMessageTemplates = map[ConditionReason]string{
+	ReasonTargetRefMissing:           "Cannot read targetRef",
+	ReasonTargetRefFetchFailed:       "Cannot read targetRef. Reason: {{error}}",
+	ReasonBothSelectorsDefined:       "Both targetRef and label selector defined. Please reomve label selector",
+	ReasonLegacySelectorDeprecated:   "Deprecated label selector defined, please migrate to targetRef",
+	ReasonTargetRefHasParent:         "The targetRef controller has a parent but it should point to a top-level controller",
+	ReasonTopLevelLookupError:        "Error checking if target is a top level controller: {{error}}",
+	ReasonTargetAPIVersionDeprecated: "targetRef points to {{apiVersion}} {{kind}}, which is deprecated as of Kubernetes {{deprecatedInVersion}} and still served on this cluster ({{clusterVersion}})",
+	ReasonTargetKindRemoved:          "targetRef points to {{apiVersion}} {{kind}}, which was removed from the Kubernetes API in {{removedInVersion}} and is no longer reachable on this cluster ({{clusterVersion}})",
+	ReasonTargetControllerDeprecated: "targetRef points to {{apiVersion}} {{kind}}, which is deprecated and may be removed in a future Kubernetes release",
+	ReasonControllerUpgradeAvailable: "A newer version of the target controller is available: {{versions}}",
+}
+
+// ConditionDetail bundles a structured condition Reason with the Params
+// used to render its message, letting callers build a
+// VerticalPodAutoscalerCondition without hand-formatting English text.
+type ConditionDetail struct {
+	Reason ConditionReason
+	Params map[string]string
+}
+
+// FormatCondition renders detail's Reason and Params into the
+// human-readable message `kubectl describe vpa` shows, using the template
+// registered for detail.Reason. An unregistered Reason renders as itself,
+// so a caller that adds a Reason but forgets its template still gets a
+// readable (if unsubstituted) message instead of a panic.
+func FormatCondition(detail ConditionDetail) string {
+	template, ok := conditionMessageTemplates[detail.Reason]
+	if !ok {
+		return string(detail.Reason)
+	}
+	message := template
+	for key, value := range detail.Params {
+		message = strings.ReplaceAll(message, "{{"+key+"}}", value)
+	}
+	return message
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package v1beta2 contains definitions of Vertical Pod Autoscaler related objects.
+package v1beta2
+
+import (
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// VerticalPodAutoscaler is the configuration for a vertical pod
+// autoscaler, which automatically manages pod resources based on historical and
+// real time resource utilization.
+type VerticalPodAutoscaler struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec defines the behavior of the autoscaler.
+	Spec VerticalPodAutoscalerSpec `json:"spec"`
+
+	// Status describes the runtime state of the autoscaler.
+	// +optional
+	Status VerticalPodAutoscalerStatus `json:"status,omitempty"`
+}
+
+// VerticalPodAutoscalerSpec is the specification of the behavior of the autoscaler.
+type VerticalPodAutoscalerSpec struct {
+	// TargetRef points to the controller managing the set of pods for the
+	// autoscaler to control.
+	TargetRef *autoscalingv1.CrossVersionObjectReference `json:"targetRef,omitempty"`
+}
+
+// VerticalPodAutoscalerStatus describes the runtime state of the autoscaler.
+type VerticalPodAutoscalerStatus struct {
+	// Conditions is the set of conditions required for this autoscaler to scale its target,
+	// and indicates whether or not those conditions are met.
+	// +optional
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	Conditions []VerticalPodAutoscalerCondition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+}
+
+// VerticalPodAutoscalerConditionType are the valid conditions of
+// a VerticalPodAutoscaler.
+type VerticalPodAutoscalerConditionType string
+
+var (
+	// RecommendationProvided indicates whether the VPA recommender was able to calculate a recommendation.
+	RecommendationProvided VerticalPodAutoscalerConditionType = "RecommendationProvided"
+	// LowConfidence indicates whether the VPA recommender has low confidence in the recommendation for
+	// some of containers.
+	LowConfidence VerticalPodAutoscalerConditionType = "LowConfidence"
+	// NoPodsMatched indicates that label selector used with VPA object didn't match any pods.
+	NoPodsMatched VerticalPodAutoscalerConditionType = "NoPodsMatched"
+	// FetchingHistory indicates that VPA recommender is in the process of loading additional history samples.
+	FetchingHistory VerticalPodAutoscalerConditionType = "FetchingHistory"
+	// ConfigDeprecated indicates that this VPA configuration is deprecated
+	// and will stop being supported soon.
+	ConfigDeprecated VerticalPodAutoscalerConditionType = "ConfigDeprecated"
+	// ConfigUnsupported indicates that this VPA configuration is unsupported
+	// and recommendations will not be provided for it.
+	ConfigUnsupported VerticalPodAutoscalerConditionType = "ConfigUnsupported"
+	// TargetDeprecated indicates that the VPA's targetRef points to a
+	// controller whose API version is deprecated or removed in the
+	// connected cluster's server version.
+	TargetDeprecated VerticalPodAutoscalerConditionType = "TargetDeprecated"
+	// ConfigUpgradeAvailable indicates that a newer version of the VPA's
+	// targetRef controller is reachable, either via a configured semver
+	// constraint or the controller's own replaces/skips/skipRange
+	// successor annotations.
+	ConfigUpgradeAvailable VerticalPodAutoscalerConditionType = "ConfigUpgradeAvailable"
+)
+
+// VerticalPodAutoscalerCondition describes the state of
+// a VerticalPodAutoscaler at a certain point.
+type VerticalPodAutoscalerCondition struct {
+	// Type of VerticalPodAutoscaler condition.
+	Type VerticalPodAutoscalerConditionType `json:"type"`
+	// Status is the status of the condition (True, False, Unknown).
+	Status v1.ConditionStatus `json:"status"`
+	// Last time the condition transitioned from one status to another.
+	// +optional
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+	// Unique, one-word, CamelCase reason for the condition's last transition.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+	// Human-readable message indicating details about last transition.
+	// +optional
+	Message string `json:"message,omitempty"`
+	// Params holds the structured values Message was rendered from, for
+	// tooling that wants to consume a condition without parsing Message.
+	// +optional
+	Params map[string]string `json:"params,omitempty"`
+}
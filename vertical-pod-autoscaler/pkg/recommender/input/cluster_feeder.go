@@ -0,0 +1,206 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package input
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/labels"
+	vpa_types "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1beta2"
+	controllerfetcher "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/recommender/input/controller_fetcher"
+	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/recommender/model"
+	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/target"
+)
+
+// beta1APIDeprecated tracks whether the cluster's API server has removed the
+// v1beta1 variant of the VPA CRD selector path. It is a package-level
+// variable (rather than a feeder field) because it reflects a property of
+// the connected API server discovered once at startup, not per-feeder state.
+var beta1APIDeprecated = new(bool)
+
+// vpaLister lists the VerticalPodAutoscaler objects the feeder should track.
+type vpaLister interface {
+	List() ([]*vpa_types.VerticalPodAutoscaler, error)
+}
+
+// clusterStateFeeder populates a model.ClusterState from the cluster's VPA
+// objects, resolving each one's pod selector and reporting any
+// configuration problems back as conditions on the stored Vpa.
+type clusterStateFeeder struct {
+	vpaLister             vpaLister
+	clusterState          *model.ClusterState
+	legacySelectorFetcher target.VpaTargetSelectorFetcher
+	selectorFetcher       target.VpaTargetSelectorFetcher
+	controllerFetcher     controllerfetcher.ControllerFetcher
+
+	// clusterVersion is the connected cluster's "major.minor" server
+	// version, used to decide whether a targetRef's API has been removed.
+	// Left empty, deprecation rollup conditions are skipped.
+	clusterVersion string
+
+	// trackingMethods are additional TargetTrackingMethods, tried in
+	// order, for VPAs that don't resolve via targetRef or the legacy
+	// label selector. Left empty, such VPAs are reported as unsupported,
+	// as before this field existed.
+	trackingMethods []TargetTrackingMethod
+
+	// upgradeConstraint is a semver constraint (e.g. ">1.2.0") describing
+	// which newer top-level controller versions should be surfaced via
+	// the ConfigUpgradeAvailable condition. Left empty, only successor
+	// versions named by the controller's own replaces/skips/skipRange
+	// annotations are surfaced.
+	upgradeConstraint string
+}
+
+// LoadVPAs fetches VPA objects and stores the ones that need to be tracked,
+// resolving their pod selector and setting any applicable conditions.
+func (feeder *clusterStateFeeder) LoadVPAs() {
+	vpaCRDs, err := feeder.vpaLister.List()
+	if err != nil {
+		return
+	}
+
+	for _, vpaCRD := range vpaCRDs {
+		vpaID := model.VpaID{
+			Namespace: vpaCRD.Namespace,
+			VpaName:   vpaCRD.Name,
+		}
+		vpa := feeder.clusterState.AddOrUpdateVpa(vpaID)
+
+		selector, fetchErr := feeder.selectorFetcher.Fetch(vpaCRD)
+		legacySelector, _ := feeder.legacySelectorFetcher.Fetch(vpaCRD)
+		legacyDeprecated := feeder.isLegacySelectorDeprecated(vpaCRD)
+
+		switch {
+		case legacySelector != nil && selector != nil:
+			vpa.PodSelector = labels.Nothing()
+			vpa.Conditions.SetDetail(vpa_types.ConfigUnsupported, true, vpa_types.ConditionDetail{
+				Reason: vpa_types.ReasonBothSelectorsDefined,
+			})
+		case legacySelector != nil:
+			vpa.PodSelector = legacySelector
+			vpa.TrackingMethod = string(TrackingMethodLabel)
+			if legacyDeprecated {
+				vpa.Conditions.SetDetail(vpa_types.ConfigDeprecated, true, vpa_types.ConditionDetail{
+					Reason: vpa_types.ReasonLegacySelectorDeprecated,
+				})
+			}
+		case selector != nil:
+			vpa.PodSelector = selector
+			vpa.TrackingMethod = string(TrackingMethodTargetRef)
+		default:
+			if !feeder.resolveWithTrackingMethods(vpaCRD, vpa) {
+				vpa.PodSelector = labels.Nothing()
+				if fetchErr != nil {
+					vpa.Conditions.SetDetail(vpa_types.ConfigUnsupported, true, vpa_types.ConditionDetail{
+						Reason: vpa_types.ReasonTargetRefFetchFailed,
+						Params: map[string]string{"error": fetchErr.Error()},
+					})
+				} else {
+					vpa.Conditions.SetDetail(vpa_types.ConfigUnsupported, true, vpa_types.ConditionDetail{
+						Reason: vpa_types.ReasonTargetRefMissing,
+					})
+				}
+			}
+		}
+
+		feeder.validateTargetRefTopLevel(vpaCRD, vpa)
+	}
+}
+
+// resolveWithTrackingMethods tries feeder's configured trackingMethods in
+// order, applying the first one that resolves vpaCRD's tracked pods. It
+// returns false, leaving vpa untouched, if none of them apply.
+func (feeder *clusterStateFeeder) resolveWithTrackingMethods(vpaCRD *vpa_types.VerticalPodAutoscaler, vpa *model.Vpa) bool {
+	for _, method := range feeder.trackingMethods {
+		result, err := method.Resolve(vpaCRD)
+		if err != nil || result == nil {
+			continue
+		}
+		vpa.PodSelector = result.Selector
+		vpa.PodMatcher = result.Matcher
+		vpa.TrackingMethod = string(method.Name())
+		return true
+	}
+	return false
+}
+
+// isLegacySelectorDeprecated reports whether the VPA still relies on the
+// removed v1beta1 label-selector path on an API server that no longer
+// serves it.
+func (feeder *clusterStateFeeder) isLegacySelectorDeprecated(vpaCRD *vpa_types.VerticalPodAutoscaler) bool {
+	legacySelector, _ := feeder.legacySelectorFetcher.Fetch(vpaCRD)
+	return legacySelector != nil && *beta1APIDeprecated
+}
+
+// validateTargetRefTopLevel checks that a VPA's targetRef (if any) points
+// directly at a top-level controller, setting a Warning condition when it
+// doesn't, and rolls up deprecation warnings for the resolved controller's
+// API.
+func (feeder *clusterStateFeeder) validateTargetRefTopLevel(vpaCRD *vpa_types.VerticalPodAutoscaler, vpa *model.Vpa) {
+	if vpaCRD.Spec.TargetRef == nil {
+		return
+	}
+
+	key := &controllerfetcher.ControllerKeyWithAPIVersion{
+		ControllerKey: controllerfetcher.ControllerKey{
+			Namespace: vpaCRD.Namespace,
+			Kind:      vpaCRD.Spec.TargetRef.Kind,
+			Name:      vpaCRD.Spec.TargetRef.Name,
+		},
+		ApiVersion: vpaCRD.Spec.TargetRef.APIVersion,
+	}
+
+	topLevelKey, upgradeCandidates, err := feeder.controllerFetcher.FindTopLevelWithUpgradePath(key, feeder.upgradeConstraint)
+	if err != nil {
+		vpa.Conditions.SetDetail(vpa_types.ConfigUnsupported, true, vpa_types.ConditionDetail{
+			Reason: vpa_types.ReasonTopLevelLookupError,
+			Params: map[string]string{"error": err.Error()},
+		})
+		return
+	}
+	if topLevelKey == nil {
+		return
+	}
+	if *topLevelKey != *key {
+		vpa.Conditions.SetDetail(vpa_types.ConfigUnsupported, true, vpa_types.ConditionDetail{
+			Reason: vpa_types.ReasonTargetRefHasParent,
+		})
+		return
+	}
+
+	feeder.setTargetDeprecatedCondition(topLevelKey, vpa)
+	feeder.setUpgradeAvailableCondition(upgradeCandidates, vpa)
+}
+
+// setUpgradeAvailableCondition sets the ConfigUpgradeAvailable condition
+// when candidates is non-empty, listing the reachable versions.
+func (feeder *clusterStateFeeder) setUpgradeAvailableCondition(candidates []controllerfetcher.UpgradeCandidate, vpa *model.Vpa) {
+	if len(candidates) == 0 {
+		return
+	}
+
+	versions := make([]string, 0, len(candidates))
+	for _, candidate := range candidates {
+		versions = append(versions, fmt.Sprintf("%s (%s)", candidate.ApiVersion, candidate.Reason))
+	}
+	vpa.Conditions.SetDetail(vpa_types.ConfigUpgradeAvailable, true, vpa_types.ConditionDetail{
+		Reason: vpa_types.ReasonControllerUpgradeAvailable,
+		Params: map[string]string{"versions": strings.Join(versions, ", ")},
+	})
+}
@@ -34,8 +34,13 @@ import (
 )
 
 type fakeControllerFetcher struct {
-	key *controllerfetcher.ControllerKeyWithAPIVersion
-	err error
+	key               *controllerfetcher.ControllerKeyWithAPIVersion
+	err               error
+	upgradeCandidates []controllerfetcher.UpgradeCandidate
+}
+
+func (f *fakeControllerFetcher) FindTopLevelWithUpgradePath(controller *controllerfetcher.ControllerKeyWithAPIVersion, constraint string) (*controllerfetcher.ControllerKeyWithAPIVersion, []controllerfetcher.UpgradeCandidate, error) {
+	return f.key, f.upgradeCandidates, f.err
 }
 
 func (f *fakeControllerFetcher) FindTopLevel(controller *controllerfetcher.ControllerKeyWithAPIVersion) (*controllerfetcher.ControllerKeyWithAPIVersion, error) {
@@ -48,16 +53,23 @@ func parseLabelSelector(selector string) labels.Selector {
 	return parsedSelector
 }
 
-var (
-	deprecatedConditionText               = "Deprecated label selector defined, please migrate to targetRef"
-	unsupportedConditionTextFromFetcher   = "Cannot read targetRef. Reason: targetRef not defined"
-	unsupportedConditionNoExtraText       = "Cannot read targetRef"
-	unsupportedConditionBothDefined       = "Both targetRef and label selector defined. Please reomve label selector"
-	unsupportedConditionNoLongerSupported = "Label selector is no longer supported, please migrate to targetRef"
-	unsupportedConditionNoTargetRef       = "Cannot read targetRef"
-	unsupportedConditionMudaMudaMuda      = "Error checking if target is a top level controller: muda muda muda"
-	unsupportedTargetRefHasParent         = "The targetRef controller has a parent but it should point to a top-level controller"
-)
+// reasonPtr returns a pointer to reason, for use in table-driven test cases
+// that need to distinguish "no condition expected" (nil) from "this reason
+// is expected" (non-nil), mirroring the *string convention these test cases
+// used before conditions carried a structured Reason.
+func reasonPtr(reason vpa_types.ConditionReason) *vpa_types.ConditionReason {
+	return &reason
+}
+
+// assertConditionMatches asserts that condition carries wantReason and
+// wantParams, and that its Message is exactly what FormatCondition renders
+// for them, so these test cases double as a check that the template
+// registered for each reason stays in sync with its Params.
+func assertConditionMatches(t *testing.T, condition vpa_types.VerticalPodAutoscalerCondition, wantReason vpa_types.ConditionReason, wantParams map[string]string) {
+	assert.Equal(t, string(wantReason), condition.Reason)
+	assert.Equal(t, wantParams, condition.Params)
+	assert.Equal(t, vpa_types.FormatCondition(vpa_types.ConditionDetail{Reason: wantReason, Params: wantParams}), condition.Message)
+}
 
 const (
 	kind       = "dodokind"
@@ -70,91 +82,92 @@ const (
 func TestLegacySelector(t *testing.T) {
 
 	type testCase struct {
-		name                      string
-		legacySelector            labels.Selector
-		selector                  labels.Selector
-		beta1APIDeprecated        bool
-		fetchSelectorError        error
-		targetRef                 *v1.CrossVersionObjectReference
-		topLevelKey               *controllerfetcher.ControllerKeyWithAPIVersion
-		findTopLevelError         error
-		expectedSelector          labels.Selector
-		expectedConfigUnsupported *string
-		expectedConfigDeprecated  *string
+		name                                 string
+		legacySelector                       labels.Selector
+		selector                             labels.Selector
+		beta1APIDeprecated                   bool
+		fetchSelectorError                   error
+		targetRef                            *v1.CrossVersionObjectReference
+		topLevelKey                          *controllerfetcher.ControllerKeyWithAPIVersion
+		findTopLevelError                    error
+		clusterVersion                       string
+		useAnnotationTracking                bool
+		upgradeCandidates                    []controllerfetcher.UpgradeCandidate
+		expectedSelector                     labels.Selector
+		expectedConfigUnsupportedReason      *vpa_types.ConditionReason
+		expectedConfigUnsupportedParams      map[string]string
+		expectedConfigDeprecatedReason       *vpa_types.ConditionReason
+		expectedTargetDeprecatedReason       *vpa_types.ConditionReason
+		expectedTargetDeprecatedParams       map[string]string
+		expectedTrackingMethod               string
+		expectedConfigUpgradeAvailableReason *vpa_types.ConditionReason
+		expectedConfigUpgradeAvailableParams map[string]string
 	}
 
 	testCases := []testCase{
 		{
-			name:                      "no selector",
-			legacySelector:            nil,
-			selector:                  nil,
-			beta1APIDeprecated:        true,
-			fetchSelectorError:        fmt.Errorf("targetRef not defined"),
-			expectedSelector:          labels.Nothing(),
-			expectedConfigUnsupported: &unsupportedConditionTextFromFetcher,
-			expectedConfigDeprecated:  nil,
+			name:                            "no selector",
+			legacySelector:                  nil,
+			selector:                        nil,
+			beta1APIDeprecated:              true,
+			fetchSelectorError:              fmt.Errorf("targetRef not defined"),
+			expectedSelector:                labels.Nothing(),
+			expectedConfigUnsupportedReason: reasonPtr(vpa_types.ReasonTargetRefFetchFailed),
+			expectedConfigUnsupportedParams: map[string]string{"error": "targetRef not defined"},
 		},
 		{
-			name:                      "also no selector but no error",
-			legacySelector:            nil,
-			selector:                  nil,
-			beta1APIDeprecated:        true,
-			fetchSelectorError:        nil,
-			expectedSelector:          labels.Nothing(),
-			expectedConfigUnsupported: &unsupportedConditionNoExtraText,
-			expectedConfigDeprecated:  nil,
+			name:                            "also no selector but no error",
+			legacySelector:                  nil,
+			selector:                        nil,
+			beta1APIDeprecated:              true,
+			fetchSelectorError:              nil,
+			expectedSelector:                labels.Nothing(),
+			expectedConfigUnsupportedReason: reasonPtr(vpa_types.ReasonTargetRefMissing),
 		},
 		{
-			name:                      "legacy selector no ref",
-			legacySelector:            parseLabelSelector("app = test"),
-			selector:                  nil,
-			beta1APIDeprecated:        true,
-			fetchSelectorError:        fmt.Errorf("targetRef not defined"),
-			expectedSelector:          parseLabelSelector("app = test"),
-			expectedConfigUnsupported: nil,
-			expectedConfigDeprecated:  &deprecatedConditionText,
+			name:                           "legacy selector no ref",
+			legacySelector:                 parseLabelSelector("app = test"),
+			selector:                       nil,
+			beta1APIDeprecated:             true,
+			fetchSelectorError:             fmt.Errorf("targetRef not defined"),
+			expectedSelector:               parseLabelSelector("app = test"),
+			expectedConfigDeprecatedReason: reasonPtr(vpa_types.ReasonLegacySelectorDeprecated),
 		}, {
-			legacySelector:            nil,
-			selector:                  parseLabelSelector("app = test"),
-			beta1APIDeprecated:        true,
-			fetchSelectorError:        nil,
-			expectedSelector:          parseLabelSelector("app = test"),
-			expectedConfigUnsupported: nil,
-			expectedConfigDeprecated:  nil,
+			legacySelector:     nil,
+			selector:           parseLabelSelector("app = test"),
+			beta1APIDeprecated: true,
+			fetchSelectorError: nil,
+			expectedSelector:   parseLabelSelector("app = test"),
 		}, {
-			legacySelector:            parseLabelSelector("app = test1"),
-			selector:                  parseLabelSelector("app = test2"),
-			beta1APIDeprecated:        true,
-			fetchSelectorError:        nil,
-			expectedSelector:          labels.Nothing(),
-			expectedConfigUnsupported: &unsupportedConditionBothDefined,
-			expectedConfigDeprecated:  nil,
+			legacySelector:                  parseLabelSelector("app = test1"),
+			selector:                        parseLabelSelector("app = test2"),
+			beta1APIDeprecated:              true,
+			fetchSelectorError:              nil,
+			expectedSelector:                labels.Nothing(),
+			expectedConfigUnsupportedReason: reasonPtr(vpa_types.ReasonBothSelectorsDefined),
 		}, {
-			legacySelector:            nil,
-			selector:                  nil,
-			beta1APIDeprecated:        false,
-			fetchSelectorError:        fmt.Errorf("targetRef not defined"),
-			expectedSelector:          labels.Nothing(),
-			expectedConfigUnsupported: &unsupportedConditionTextFromFetcher,
-			expectedConfigDeprecated:  nil,
+			legacySelector:                  nil,
+			selector:                        nil,
+			beta1APIDeprecated:              false,
+			fetchSelectorError:              fmt.Errorf("targetRef not defined"),
+			expectedSelector:                labels.Nothing(),
+			expectedConfigUnsupportedReason: reasonPtr(vpa_types.ReasonTargetRefFetchFailed),
+			expectedConfigUnsupportedParams: map[string]string{"error": "targetRef not defined"},
 		},
 		{
-			legacySelector:            nil,
-			selector:                  nil,
-			beta1APIDeprecated:        false,
-			fetchSelectorError:        nil,
-			expectedSelector:          labels.Nothing(),
-			expectedConfigUnsupported: &unsupportedConditionNoExtraText,
-			expectedConfigDeprecated:  nil,
+			legacySelector:                  nil,
+			selector:                        nil,
+			beta1APIDeprecated:              false,
+			fetchSelectorError:              nil,
+			expectedSelector:                labels.Nothing(),
+			expectedConfigUnsupportedReason: reasonPtr(vpa_types.ReasonTargetRefMissing),
 		},
 		{
-			legacySelector:            parseLabelSelector("app = test"),
-			selector:                  nil,
-			beta1APIDeprecated:        false,
-			fetchSelectorError:        fmt.Errorf("targetRef not defined"),
-			expectedSelector:          parseLabelSelector("app = test"),
-			expectedConfigUnsupported: nil,
-			expectedConfigDeprecated:  nil,
+			legacySelector:     parseLabelSelector("app = test"),
+			selector:           nil,
+			beta1APIDeprecated: false,
+			fetchSelectorError: fmt.Errorf("targetRef not defined"),
+			expectedSelector:   parseLabelSelector("app = test"),
 		}, {
 			name: "targetRef selector",
 			// the only valid option since v1beta1 removal
@@ -174,18 +187,15 @@ func TestLegacySelector(t *testing.T) {
 				},
 				ApiVersion: apiVersion,
 			},
-			expectedSelector:          parseLabelSelector("app = test"),
-			expectedConfigUnsupported: nil,
-			expectedConfigDeprecated:  nil,
+			expectedSelector: parseLabelSelector("app = test"),
 		}, {
-			name:                      "new and legacy selector",
-			legacySelector:            parseLabelSelector("app = test1"),
-			selector:                  parseLabelSelector("app = test2"),
-			beta1APIDeprecated:        false,
-			fetchSelectorError:        nil,
-			expectedSelector:          labels.Nothing(),
-			expectedConfigUnsupported: &unsupportedConditionBothDefined,
-			expectedConfigDeprecated:  nil,
+			name:                            "new and legacy selector",
+			legacySelector:                  parseLabelSelector("app = test1"),
+			selector:                        parseLabelSelector("app = test2"),
+			beta1APIDeprecated:              false,
+			fetchSelectorError:              nil,
+			expectedSelector:                labels.Nothing(),
+			expectedConfigUnsupportedReason: reasonPtr(vpa_types.ReasonBothSelectorsDefined),
 		},
 		{
 			name:               "can't decide if top-level-ref",
@@ -198,7 +208,7 @@ func TestLegacySelector(t *testing.T) {
 				Name:       name1,
 				APIVersion: apiVersion,
 			},
-			expectedConfigUnsupported: &unsupportedConditionNoTargetRef,
+			expectedConfigUnsupportedReason: reasonPtr(vpa_types.ReasonTargetRefMissing),
 		},
 		{
 			name:               "non-top-level targetRef",
@@ -219,7 +229,7 @@ func TestLegacySelector(t *testing.T) {
 				},
 				ApiVersion: apiVersion,
 			},
-			expectedConfigUnsupported: &unsupportedTargetRefHasParent,
+			expectedConfigUnsupportedReason: reasonPtr(vpa_types.ReasonTargetRefHasParent),
 		},
 		{
 			name:               "error checking if top-level-ref",
@@ -232,8 +242,9 @@ func TestLegacySelector(t *testing.T) {
 				Name:       "doseph-doestar",
 				APIVersion: "taxonomy",
 			},
-			expectedConfigUnsupported: &unsupportedConditionMudaMudaMuda,
-			findTopLevelError:         fmt.Errorf("muda muda muda"),
+			expectedConfigUnsupportedReason: reasonPtr(vpa_types.ReasonTopLevelLookupError),
+			expectedConfigUnsupportedParams: map[string]string{"error": "muda muda muda"},
+			findTopLevelError:               fmt.Errorf("muda muda muda"),
 		},
 		{
 			name:               "top-level target ref",
@@ -254,7 +265,96 @@ func TestLegacySelector(t *testing.T) {
 				},
 				ApiVersion: apiVersion,
 			},
-			expectedConfigUnsupported: nil,
+		},
+		{
+			name:               "targetRef API removed on connected cluster",
+			legacySelector:     nil,
+			selector:           parseLabelSelector("app = test"),
+			fetchSelectorError: nil,
+			expectedSelector:   parseLabelSelector("app = test"),
+			targetRef: &v1.CrossVersionObjectReference{
+				Kind:       "Deployment",
+				Name:       name1,
+				APIVersion: "extensions/v1beta1",
+			},
+			topLevelKey: &controllerfetcher.ControllerKeyWithAPIVersion{
+				ControllerKey: controllerfetcher.ControllerKey{
+					Kind:      "Deployment",
+					Name:      name1,
+					Namespace: namespace,
+				},
+				ApiVersion: "extensions/v1beta1",
+			},
+			clusterVersion:                 "1.20",
+			expectedTargetDeprecatedReason: reasonPtr(vpa_types.ReasonTargetKindRemoved),
+			expectedTargetDeprecatedParams: map[string]string{
+				"apiVersion":       "extensions/v1beta1",
+				"kind":             "Deployment",
+				"removedInVersion": "1.16",
+				"clusterVersion":   "1.20",
+			},
+		},
+		{
+			name:               "targetRef API deprecated but still reachable on connected cluster",
+			legacySelector:     nil,
+			selector:           parseLabelSelector("app = test"),
+			fetchSelectorError: nil,
+			expectedSelector:   parseLabelSelector("app = test"),
+			targetRef: &v1.CrossVersionObjectReference{
+				Kind:       "CronJob",
+				Name:       name1,
+				APIVersion: "batch/v1beta1",
+			},
+			topLevelKey: &controllerfetcher.ControllerKeyWithAPIVersion{
+				ControllerKey: controllerfetcher.ControllerKey{
+					Kind:      "CronJob",
+					Name:      name1,
+					Namespace: namespace,
+				},
+				ApiVersion: "batch/v1beta1",
+			},
+			clusterVersion:                 "v1.22+",
+			expectedTargetDeprecatedReason: reasonPtr(vpa_types.ReasonTargetAPIVersionDeprecated),
+			expectedTargetDeprecatedParams: map[string]string{
+				"apiVersion":          "batch/v1beta1",
+				"kind":                "CronJob",
+				"deprecatedInVersion": "1.21",
+				"clusterVersion":      "v1.22+",
+			},
+		},
+		{
+			name:                   "falls back to annotation tracking",
+			legacySelector:         nil,
+			selector:               nil,
+			fetchSelectorError:     fmt.Errorf("targetRef not defined"),
+			useAnnotationTracking:  true,
+			expectedSelector:       nil,
+			expectedTrackingMethod: string(TrackingMethodAnnotation),
+		},
+		{
+			name:               "newer controller version reachable",
+			legacySelector:     nil,
+			selector:           parseLabelSelector("app = test"),
+			fetchSelectorError: nil,
+			expectedSelector:   parseLabelSelector("app = test"),
+			targetRef: &v1.CrossVersionObjectReference{
+				Kind:       kind,
+				Name:       name1,
+				APIVersion: apiVersion,
+			},
+			topLevelKey: &controllerfetcher.ControllerKeyWithAPIVersion{
+				ControllerKey: controllerfetcher.ControllerKey{
+					Kind:      kind,
+					Name:      name1,
+					Namespace: namespace,
+				},
+				ApiVersion: apiVersion,
+			},
+			upgradeCandidates: []controllerfetcher.UpgradeCandidate{
+				{ApiVersion: "v2", Reason: "replaces"},
+			},
+			expectedConfigUpgradeAvailableReason: reasonPtr(vpa_types.ReasonControllerUpgradeAvailable),
+			expectedConfigUpgradeAvailableParams: map[string]string{"versions": "v2 (replaces)"},
 		},
 	}
 
@@ -280,9 +380,14 @@ func TestLegacySelector(t *testing.T) {
 				legacySelectorFetcher: legacyTargetSelectorFetcher,
 				selectorFetcher:       targetSelectorFetcher,
 				controllerFetcher: &fakeControllerFetcher{
-					key: tc.topLevelKey,
-					err: tc.findTopLevelError,
+					key:               tc.topLevelKey,
+					err:               tc.findTopLevelError,
+					upgradeCandidates: tc.upgradeCandidates,
 				},
+				clusterVersion: tc.clusterVersion,
+			}
+			if tc.useAnnotationTracking {
+				clusterStateFeeder.trackingMethods = []TargetTrackingMethod{NewAnnotationTrackingMethod()}
 			}
 
 			// legacyTargetSelectorFetcher is called twice:
@@ -306,20 +411,41 @@ func TestLegacySelector(t *testing.T) {
 				assert.Nil(t, storedVpa.PodSelector)
 			}
 
-			if tc.expectedConfigDeprecated != nil {
+			if tc.expectedConfigDeprecatedReason != nil {
 				assert.Contains(t, storedVpa.Conditions, vpa_types.ConfigDeprecated)
-				assert.Equal(t, *tc.expectedConfigDeprecated, storedVpa.Conditions[vpa_types.ConfigDeprecated].Message)
+				assertConditionMatches(t, storedVpa.Conditions[vpa_types.ConfigDeprecated], *tc.expectedConfigDeprecatedReason, nil)
 			} else {
 				assert.NotContains(t, storedVpa.Conditions, vpa_types.ConfigDeprecated)
 			}
 
-			if tc.expectedConfigUnsupported != nil {
+			if tc.expectedConfigUnsupportedReason != nil {
 				assert.Contains(t, storedVpa.Conditions, vpa_types.ConfigUnsupported)
-				assert.Equal(t, *tc.expectedConfigUnsupported, storedVpa.Conditions[vpa_types.ConfigUnsupported].Message)
+				assertConditionMatches(t, storedVpa.Conditions[vpa_types.ConfigUnsupported], *tc.expectedConfigUnsupportedReason, tc.expectedConfigUnsupportedParams)
 			} else {
 				assert.NotContains(t, storedVpa.Conditions, vpa_types.ConfigUnsupported)
 			}
 
+			if tc.expectedTargetDeprecatedReason != nil {
+				assert.Contains(t, storedVpa.Conditions, vpa_types.TargetDeprecated)
+				assertConditionMatches(t, storedVpa.Conditions[vpa_types.TargetDeprecated], *tc.expectedTargetDeprecatedReason, tc.expectedTargetDeprecatedParams)
+			} else {
+				assert.NotContains(t, storedVpa.Conditions, vpa_types.TargetDeprecated)
+			}
+
+			if tc.useAnnotationTracking {
+				assert.NotNil(t, storedVpa.PodMatcher)
+			}
+			if tc.expectedTrackingMethod != "" {
+				assert.Equal(t, tc.expectedTrackingMethod, storedVpa.TrackingMethod)
+			}
+
+			if tc.expectedConfigUpgradeAvailableReason != nil {
+				assert.Contains(t, storedVpa.Conditions, vpa_types.ConfigUpgradeAvailable)
+				assertConditionMatches(t, storedVpa.Conditions[vpa_types.ConfigUpgradeAvailable], *tc.expectedConfigUpgradeAvailableReason, tc.expectedConfigUpgradeAvailableParams)
+			} else {
+				assert.NotContains(t, storedVpa.Conditions, vpa_types.ConfigUpgradeAvailable)
+			}
+
 		})
 	}
 
@@ -0,0 +1,71 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package controllerfetcher resolves the top-level controller that owns a
+// given object, walking through any chain of owner references in between.
+package controllerfetcher
+
+import (
+	"fmt"
+
+	v1 "k8s.io/api/autoscaling/v1"
+)
+
+// ControllerKey identifies a controller, e.g. a Deployment or a StatefulSet.
+type ControllerKey struct {
+	Namespace string
+	Kind      string
+	Name      string
+}
+
+// ControllerKeyWithAPIVersion is a ControllerKey with an additionally
+// specified ApiVersion, as discovered by the fetcher.
+type ControllerKeyWithAPIVersion struct {
+	ControllerKey
+	ApiVersion string
+}
+
+// ControllerFetcher resolves the top level controller for a given controller
+// key, e.g. a ReplicaSet owned by a Deployment resolves to that Deployment.
+type ControllerFetcher interface {
+	// FindTopLevel returns the topmost controller that owns (possibly
+	// transitively) the given controller, or nil if the controller has no
+	// parent.
+	FindTopLevel(controller *ControllerKeyWithAPIVersion) (*ControllerKeyWithAPIVersion, error)
+
+	// FindTopLevelWithUpgradePath behaves like FindTopLevel, additionally
+	// returning the set of UpgradeCandidate versions of the resolved
+	// top-level controller that are reachable via constraint (a semver
+	// constraint, e.g. ">1.2.0") or via the owning CR's
+	// replaces/skips/skipRange successor annotations.
+	FindTopLevelWithUpgradePath(controller *ControllerKeyWithAPIVersion, constraint string) (*ControllerKeyWithAPIVersion, []UpgradeCandidate, error)
+}
+
+// NewControllerKeyWithAPIVersionFromCrossVersionObjectReference builds a
+// ControllerKeyWithAPIVersion from the targetRef stored on a VPA object.
+func NewControllerKeyWithAPIVersionFromCrossVersionObjectReference(reference v1.CrossVersionObjectReference, namespace string) (*ControllerKeyWithAPIVersion, error) {
+	if reference.Kind == "" || reference.Name == "" {
+		return nil, fmt.Errorf("targetRef not defined")
+	}
+	return &ControllerKeyWithAPIVersion{
+		ControllerKey: ControllerKey{
+			Namespace: namespace,
+			Kind:      reference.Kind,
+			Name:      reference.Name,
+		},
+		ApiVersion: reference.APIVersion,
+	}, nil
+}
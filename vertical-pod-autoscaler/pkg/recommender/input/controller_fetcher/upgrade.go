@@ -0,0 +1,128 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllerfetcher
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+// UpgradeCandidate is a future top-level controller version reachable from
+// the currently resolved one: either because its version satisfies a
+// semver constraint, or because the controller's owning CR explicitly lists
+// it as a successor, mirroring the operator-registry "replaces"/"skips"
+// successor graph used for progressive CRD upgrades.
+type UpgradeCandidate struct {
+	// ApiVersion is the candidate's resolved apiVersion, e.g. "v2" or "v1alpha2".
+	ApiVersion string
+	// Reason explains why this candidate was surfaced: "semver" when it
+	// matched the requested constraint, or "replaces"/"skips"/"skipRange"
+	// when it came from the corresponding annotation on the owning CR.
+	Reason string
+}
+
+// SuccessorAnnotations mirrors the operator-registry successor fields that
+// may be set on the CR owning a VPA's top-level controller, identifying
+// the versions it replaces or can skip directly to.
+type SuccessorAnnotations struct {
+	// Replaces is the single version this CR directly replaces.
+	Replaces string
+	// Skips lists additional versions this CR can be upgraded from,
+	// skipping any versions in between.
+	Skips []string
+	// SkipRange is a semver constraint describing the full range of
+	// versions this CR can be upgraded from.
+	SkipRange string
+}
+
+// ResolveUpgradeCandidates computes the set of UpgradeCandidates for a
+// controller currently at currentVersion, given the successor annotations
+// found on candidate versions and an optional semver constraint.
+func ResolveUpgradeCandidates(currentVersion string, successors map[string]SuccessorAnnotations, constraint string) ([]UpgradeCandidate, error) {
+	var constraints *semver.Constraints
+	if constraint != "" {
+		parsed, err := semver.NewConstraint(constraint)
+		if err != nil {
+			return nil, fmt.Errorf("invalid upgrade constraint %q: %v", constraint, err)
+		}
+		constraints = parsed
+	}
+
+	var candidates []UpgradeCandidate
+	for version, successor := range successors {
+		if version == currentVersion {
+			continue
+		}
+
+		if successor.Replaces == currentVersion {
+			candidates = append(candidates, UpgradeCandidate{ApiVersion: version, Reason: "replaces"})
+			continue
+		}
+		if containsString(successor.Skips, currentVersion) {
+			candidates = append(candidates, UpgradeCandidate{ApiVersion: version, Reason: "skips"})
+			continue
+		}
+		if successor.SkipRange != "" {
+			if matchesSkipRange(currentVersion, successor.SkipRange) {
+				candidates = append(candidates, UpgradeCandidate{ApiVersion: version, Reason: "skipRange"})
+				continue
+			}
+		}
+
+		if constraints != nil {
+			if v, err := semver.NewVersion(version); err == nil && constraints.Check(v) {
+				candidates = append(candidates, UpgradeCandidate{ApiVersion: version, Reason: "semver"})
+			}
+		}
+	}
+
+	// successors is a map, so iteration order (and thus the order
+	// candidates were appended above) isn't stable across calls. Sort so
+	// callers that join ApiVersion into a message get a deterministic
+	// result.
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].ApiVersion != candidates[j].ApiVersion {
+			return candidates[i].ApiVersion < candidates[j].ApiVersion
+		}
+		return candidates[i].Reason < candidates[j].Reason
+	})
+
+	return candidates, nil
+}
+
+func matchesSkipRange(version, skipRange string) bool {
+	constraints, err := semver.NewConstraint(skipRange)
+	if err != nil {
+		return false
+	}
+	v, err := semver.NewVersion(version)
+	if err != nil {
+		return false
+	}
+	return constraints.Check(v)
+}
+
+func containsString(haystack []string, needle string) bool {
+	for _, s := range haystack {
+		if s == needle {
+			return true
+		}
+	}
+	return false
+}
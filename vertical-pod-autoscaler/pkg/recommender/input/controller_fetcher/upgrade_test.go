@@ -0,0 +1,125 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllerfetcher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveUpgradeCandidates(t *testing.T) {
+	testCases := []struct {
+		name           string
+		currentVersion string
+		successors     map[string]SuccessorAnnotations
+		constraint     string
+		wantErr        bool
+		want           []UpgradeCandidate
+	}{
+		{
+			name:           "no successors",
+			currentVersion: "v1",
+			successors:     map[string]SuccessorAnnotations{},
+			want:           nil,
+		},
+		{
+			name:           "replaces",
+			currentVersion: "v1",
+			successors: map[string]SuccessorAnnotations{
+				"v2": {Replaces: "v1"},
+			},
+			want: []UpgradeCandidate{{ApiVersion: "v2", Reason: "replaces"}},
+		},
+		{
+			name:           "skips",
+			currentVersion: "v1",
+			successors: map[string]SuccessorAnnotations{
+				"v3": {Skips: []string{"v1", "v2"}},
+			},
+			want: []UpgradeCandidate{{ApiVersion: "v3", Reason: "skips"}},
+		},
+		{
+			name:           "skipRange",
+			currentVersion: "1.2.0",
+			successors: map[string]SuccessorAnnotations{
+				"1.3.0": {SkipRange: ">=1.0.0 <1.3.0"},
+			},
+			want: []UpgradeCandidate{{ApiVersion: "1.3.0", Reason: "skipRange"}},
+		},
+		{
+			name:           "skipRange not matched",
+			currentVersion: "0.9.0",
+			successors: map[string]SuccessorAnnotations{
+				"1.3.0": {SkipRange: ">=1.0.0 <1.3.0"},
+			},
+			want: nil,
+		},
+		{
+			name:           "semver constraint",
+			currentVersion: "1.2.0",
+			successors: map[string]SuccessorAnnotations{
+				"1.3.0": {},
+			},
+			constraint: ">1.2.0",
+			want:       []UpgradeCandidate{{ApiVersion: "1.3.0", Reason: "semver"}},
+		},
+		{
+			name:           "semver constraint excludes current",
+			currentVersion: "1.2.0",
+			successors: map[string]SuccessorAnnotations{
+				"1.2.0": {},
+			},
+			constraint: ">=1.0.0",
+			want:       nil,
+		},
+		{
+			name:           "result is sorted regardless of map iteration order",
+			currentVersion: "1.0.0",
+			successors: map[string]SuccessorAnnotations{
+				"1.3.0": {},
+				"1.1.0": {},
+				"1.2.0": {},
+			},
+			constraint: ">1.0.0",
+			want: []UpgradeCandidate{
+				{ApiVersion: "1.1.0", Reason: "semver"},
+				{ApiVersion: "1.2.0", Reason: "semver"},
+				{ApiVersion: "1.3.0", Reason: "semver"},
+			},
+		},
+		{
+			name:           "invalid constraint",
+			currentVersion: "1.0.0",
+			successors:     map[string]SuccessorAnnotations{},
+			constraint:     "not-a-constraint",
+			wantErr:        true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ResolveUpgradeCandidates(tc.currentVersion, tc.successors, tc.constraint)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
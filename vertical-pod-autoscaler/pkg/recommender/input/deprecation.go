@@ -0,0 +1,172 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package input
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	vpa_types "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1beta2"
+	controllerfetcher "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/recommender/input/controller_fetcher"
+	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/recommender/model"
+)
+
+// apiRemoval records the deprecation/removal schedule of a Kind served
+// under apiVersion, as "major.minor" Kubernetes releases.
+//
+// deprecatedInVersion and removedInVersion are each optional:
+//   - both set: the usual deprecate-then-remove schedule.
+//   - only deprecatedInVersion set: deprecated with no removal scheduled
+//     yet.
+//   - neither set: not expected to occur in knownAPIRemovals; findAPIRemoval
+//     callers should treat this the same as "not found".
+type apiRemoval struct {
+	apiVersion          string
+	kind                string
+	deprecatedInVersion string
+	removedInVersion    string
+}
+
+// knownAPIRemovals is a small built-in table of Kubernetes API
+// deprecations/removals relevant to objects commonly targeted by a VPA. It
+// intentionally only covers the handful of controller kinds VPAs actually
+// target; it is not a general-purpose deprecation linter.
+var knownAPIRemovals = []apiRemoval{
+	{apiVersion: "extensions/v1beta1", kind: "Deployment", deprecatedInVersion: "1.9", removedInVersion: "1.16"},
+	{apiVersion: "extensions/v1beta1", kind: "DaemonSet", deprecatedInVersion: "1.9", removedInVersion: "1.16"},
+	{apiVersion: "extensions/v1beta1", kind: "ReplicaSet", deprecatedInVersion: "1.9", removedInVersion: "1.16"},
+	{apiVersion: "apps/v1beta1", kind: "Deployment", deprecatedInVersion: "1.9", removedInVersion: "1.16"},
+	{apiVersion: "apps/v1beta2", kind: "Deployment", deprecatedInVersion: "1.9", removedInVersion: "1.16"},
+	{apiVersion: "apps/v1beta1", kind: "StatefulSet", deprecatedInVersion: "1.9", removedInVersion: "1.16"},
+	{apiVersion: "policy/v1beta1", kind: "PodSecurityPolicy", deprecatedInVersion: "1.21", removedInVersion: "1.25"},
+	{apiVersion: "autoscaling/v2beta1", kind: "HorizontalPodAutoscaler", deprecatedInVersion: "1.23", removedInVersion: "1.25"},
+	{apiVersion: "batch/v1beta1", kind: "CronJob", deprecatedInVersion: "1.21", removedInVersion: "1.25"},
+}
+
+// setTargetDeprecatedCondition rolls up the resolved top-level controller's
+// deprecation status into the TargetDeprecated condition, using the Reason
+// to distinguish the three outcomes findAPIRemoval can report: the
+// apiVersion/kind is already removed (ReasonTargetKindRemoved), still
+// served but deprecated (ReasonTargetAPIVersionDeprecated), or deprecated
+// with no scheduled removal (ReasonTargetControllerDeprecated). It is a
+// no-op when the cluster version hasn't been configured on the feeder, so
+// existing callers that don't wire one up see no behavior change.
+func (feeder *clusterStateFeeder) setTargetDeprecatedCondition(topLevelKey *controllerfetcher.ControllerKeyWithAPIVersion, vpa *model.Vpa) {
+	if feeder.clusterVersion == "" {
+		return
+	}
+
+	removal, found := findAPIRemoval(topLevelKey.ApiVersion, topLevelKey.Kind)
+	if !found {
+		return
+	}
+
+	if removal.removedInVersion != "" {
+		if removed, ok := clusterVersionAtLeast(feeder.clusterVersion, removal.removedInVersion); ok && removed {
+			vpa.Conditions.SetDetail(vpa_types.TargetDeprecated, true, vpa_types.ConditionDetail{
+				Reason: vpa_types.ReasonTargetKindRemoved,
+				Params: map[string]string{
+					"apiVersion":       topLevelKey.ApiVersion,
+					"kind":             topLevelKey.Kind,
+					"removedInVersion": removal.removedInVersion,
+					"clusterVersion":   feeder.clusterVersion,
+				},
+			})
+			return
+		}
+	}
+
+	if removal.deprecatedInVersion != "" {
+		if deprecated, ok := clusterVersionAtLeast(feeder.clusterVersion, removal.deprecatedInVersion); ok && deprecated {
+			vpa.Conditions.SetDetail(vpa_types.TargetDeprecated, true, vpa_types.ConditionDetail{
+				Reason: vpa_types.ReasonTargetAPIVersionDeprecated,
+				Params: map[string]string{
+					"apiVersion":          topLevelKey.ApiVersion,
+					"kind":                topLevelKey.Kind,
+					"deprecatedInVersion": removal.deprecatedInVersion,
+					"clusterVersion":      feeder.clusterVersion,
+				},
+			})
+			return
+		}
+	}
+
+	if removal.removedInVersion == "" {
+		vpa.Conditions.SetDetail(vpa_types.TargetDeprecated, true, vpa_types.ConditionDetail{
+			Reason: vpa_types.ReasonTargetControllerDeprecated,
+			Params: map[string]string{
+				"apiVersion": topLevelKey.ApiVersion,
+				"kind":       topLevelKey.Kind,
+			},
+		})
+	}
+}
+
+// findAPIRemoval looks up apiVersion/kind in knownAPIRemovals.
+func findAPIRemoval(apiVersion, kind string) (apiRemoval, bool) {
+	for _, removal := range knownAPIRemovals {
+		if removal.apiVersion == apiVersion && removal.kind == kind {
+			return removal, true
+		}
+	}
+	return apiRemoval{}, false
+}
+
+// clusterVersionAtLeast reports whether clusterVersion is at or past
+// threshold (both "major.minor" Kubernetes release strings), comparing
+// only the major.minor components. ok is false if either version can't be
+// parsed, in which case callers should skip the check rather than treat
+// the unparseable version as "0.0" (which would otherwise report every
+// threshold as already reached).
+func clusterVersionAtLeast(clusterVersion, threshold string) (atLeast bool, ok bool) {
+	cMajor, cMinor, err := parseMajorMinor(clusterVersion)
+	if err != nil {
+		return false, false
+	}
+	tMajor, tMinor, err := parseMajorMinor(threshold)
+	if err != nil {
+		return false, false
+	}
+	if cMajor != tMajor {
+		return cMajor > tMajor, true
+	}
+	return cMinor >= tMinor, true
+}
+
+// parseMajorMinor parses the major and minor components out of a
+// Kubernetes "major.minor[.patch]" version string, such as a server
+// GitVersion. It tolerates a leading "v" (e.g. "v1.25.3") and a
+// non-numeric minor suffix (e.g. the "25+" minor reported by some managed
+// Kubernetes distributions).
+func parseMajorMinor(version string) (major, minor int, err error) {
+	version = strings.TrimPrefix(version, "v")
+	parts := strings.SplitN(version, ".", 3)
+	if len(parts) < 2 {
+		return 0, 0, fmt.Errorf("invalid Kubernetes version %q", version)
+	}
+	major, err = strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid major version in %q: %v", version, err)
+	}
+	minorStr := strings.TrimRight(parts[1], "+")
+	minor, err = strconv.Atoi(minorStr)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid minor version in %q: %v", version, err)
+	}
+	return major, minor, nil
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package input
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/labels"
+	vpa_types "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1beta2"
+	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/target"
+)
+
+// TrackingMethodName identifies a TargetTrackingMethod, and is recorded on
+// model.Vpa.TrackingMethod when that method is the one that resolved a VPA's
+// tracked pods.
+type TrackingMethodName string
+
+const (
+	// TrackingMethodTargetRef resolves pods via the VPA's targetRef,
+	// resolved to a top-level controller and its pod template selector.
+	// This is the default, recommended tracking method.
+	TrackingMethodTargetRef TrackingMethodName = "TargetRef"
+	// TrackingMethodAnnotation resolves pods that carry a
+	// "autoscaling.k8s.io/vpa-name" annotation naming this VPA, rather
+	// than matching a selector against a targetRef's pod template. This
+	// suits GitOps setups where an operator injects the annotation
+	// directly onto pods it manages.
+	TrackingMethodAnnotation TrackingMethodName = "Annotation"
+	// TrackingMethodLabel resolves pods via the deprecated, pre-targetRef
+	// top-level label selector.
+	TrackingMethodLabel TrackingMethodName = "Label"
+)
+
+// podAnnotationVpaName is the pod annotation key consulted by
+// TrackingMethodAnnotation.
+const podAnnotationVpaName = "autoscaling.k8s.io/vpa-name"
+
+// TrackingResult is what a TargetTrackingMethod produces when it is able to
+// resolve the pods tracked by a VPA.
+type TrackingResult struct {
+	// Selector is set when the method can express the match as a label
+	// selector.
+	Selector labels.Selector
+	// Matcher is set when the method needs to inspect individual pods
+	// (e.g. their annotations) rather than a selector.
+	Matcher target.PodMatcher
+}
+
+// TargetTrackingMethod resolves the set of pods a VPA should track. The
+// feeder tries each configured method in turn, in order, using the first
+// one that applies.
+type TargetTrackingMethod interface {
+	// Name identifies this method, for diagnostics.
+	Name() TrackingMethodName
+	// Resolve attempts to resolve vpaCRD's tracked pods using this
+	// method. It returns a nil result (and a nil error) when this method
+	// does not apply to vpaCRD, so the feeder can fall through to the
+	// next configured method.
+	Resolve(vpaCRD *vpa_types.VerticalPodAutoscaler) (*TrackingResult, error)
+}
+
+// annotationTrackingMethod implements TargetTrackingMethod by matching pods
+// carrying a podAnnotationVpaName annotation equal to the VPA's name. It
+// always applies, since there is nothing on the VPA object itself that
+// opts a VPA in or out of it - the annotation lives on the pods.
+type annotationTrackingMethod struct{}
+
+// NewAnnotationTrackingMethod returns a TargetTrackingMethod that matches
+// pods carrying the podAnnotationVpaName annotation.
+func NewAnnotationTrackingMethod() TargetTrackingMethod {
+	return &annotationTrackingMethod{}
+}
+
+func (m *annotationTrackingMethod) Name() TrackingMethodName {
+	return TrackingMethodAnnotation
+}
+
+func (m *annotationTrackingMethod) Resolve(vpaCRD *vpa_types.VerticalPodAutoscaler) (*TrackingResult, error) {
+	if vpaCRD.Name == "" {
+		return nil, fmt.Errorf("VPA has no name to match pod annotations against")
+	}
+	return &TrackingResult{
+		Matcher: &target.AnnotationPodMatcher{
+			AnnotationKey:   podAnnotationVpaName,
+			AnnotationValue: vpaCRD.Name,
+		},
+	}, nil
+}
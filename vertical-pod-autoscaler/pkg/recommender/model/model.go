@@ -0,0 +1,132 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package model defines the internal representation of the cluster state
+// that the recommender reasons about.
+package model
+
+import (
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	vpa_types "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1beta2"
+	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/target"
+)
+
+// VpaID uniquely identifies a VPA object.
+type VpaID struct {
+	Namespace string
+	VpaName   string
+}
+
+// Conditions holds the current set of conditions set on a Vpa, keyed by
+// condition type so that callers can look up or overwrite a single
+// condition without scanning a list.
+type Conditions map[vpa_types.VerticalPodAutoscalerConditionType]vpa_types.VerticalPodAutoscalerCondition
+
+// SetDetail updates a condition from a structured ConditionDetail, setting
+// Reason and Params directly and rendering Message from them via
+// vpa_types.FormatCondition, so the condition stays machine-parseable via
+// Reason/Params without losing the human-readable Message.
+func (conditions Conditions) SetDetail(conditionType vpa_types.VerticalPodAutoscalerConditionType, status bool, detail vpa_types.ConditionDetail) Conditions {
+	condition, found := conditions[conditionType]
+	if !found {
+		condition = vpa_types.VerticalPodAutoscalerCondition{
+			Type: conditionType,
+		}
+	}
+	if status {
+		condition.Status = "True"
+	} else {
+		condition.Status = "False"
+	}
+	condition.Reason = string(detail.Reason)
+	condition.Params = detail.Params
+	condition.Message = vpa_types.FormatCondition(detail)
+	condition.LastTransitionTime = metav1.Now()
+	conditions[conditionType] = condition
+	return conditions
+}
+
+// Vpa holds the recommender's view of a single VerticalPodAutoscaler object:
+// its resolved pod selector and the conditions reported back onto it.
+type Vpa struct {
+	ID VpaID
+
+	// PodSelector matches pods controlled by this VPA, for tracking modes
+	// that can be expressed as a label selector.
+	PodSelector labels.Selector
+
+	// PodMatcher matches pods controlled by this VPA, for tracking modes
+	// that can't be expressed as a label selector (e.g. pod-annotation
+	// based tracking). Set instead of, not in addition to, PodSelector.
+	PodMatcher target.PodMatcher
+
+	// TrackingMethod records the name of the TargetTrackingMethod that
+	// resolved PodSelector/PodMatcher for this VPA, for diagnostics.
+	TrackingMethod string
+
+	// Conditions is the set of conditions for the VPA.
+	Conditions Conditions
+}
+
+// MatchesPod reports whether pod is tracked by this Vpa, consulting
+// PodMatcher when this Vpa's tracking method resolved one (tracking modes
+// that can't be expressed as a label selector), and falling back to
+// PodSelector otherwise. Callers that load pods should use this instead of
+// reading PodSelector/PodMatcher directly, so a new tracking method only
+// needs to populate one of the two fields to be matched correctly.
+func (vpa *Vpa) MatchesPod(pod *v1.Pod) bool {
+	if vpa.PodMatcher != nil {
+		return vpa.PodMatcher.Matches(pod)
+	}
+	if vpa.PodSelector == nil || pod == nil {
+		return false
+	}
+	return vpa.PodSelector.Matches(labels.Set(pod.Labels))
+}
+
+// NewVpa returns a new Vpa with the given id.
+func NewVpa(id VpaID) *Vpa {
+	return &Vpa{
+		ID:         id,
+		Conditions: Conditions{},
+	}
+}
+
+// ClusterState holds all the information about the cluster the recommender cares about.
+type ClusterState struct {
+	// Vpas is a map from VpaID to the Vpa object.
+	Vpas map[VpaID]*Vpa
+}
+
+// NewClusterState returns a new ClusterState with no VPAs.
+func NewClusterState() *ClusterState {
+	return &ClusterState{
+		Vpas: make(map[VpaID]*Vpa),
+	}
+}
+
+// AddOrUpdateVpa adds the given Vpa to the ClusterState, or returns the
+// existing one if already present.
+func (cluster *ClusterState) AddOrUpdateVpa(id VpaID) *Vpa {
+	vpa, found := cluster.Vpas[id]
+	if !found {
+		vpa = NewVpa(id)
+		cluster.Vpas[id] = vpa
+	}
+	return vpa
+}
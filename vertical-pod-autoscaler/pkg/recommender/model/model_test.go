@@ -0,0 +1,84 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/target"
+)
+
+type fakePodMatcher struct {
+	matches bool
+}
+
+func (m *fakePodMatcher) Matches(pod *v1.Pod) bool {
+	return m.matches
+}
+
+func TestVpaMatchesPod(t *testing.T) {
+	podWithLabel := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "test"}}}
+	podWithoutLabel := &v1.Pod{}
+
+	testCases := []struct {
+		name string
+		vpa  *Vpa
+		pod  *v1.Pod
+		want bool
+	}{
+		{
+			name: "matches via PodSelector",
+			vpa:  &Vpa{PodSelector: labels.SelectorFromSet(labels.Set{"app": "test"})},
+			pod:  podWithLabel,
+			want: true,
+		},
+		{
+			name: "does not match via PodSelector",
+			vpa:  &Vpa{PodSelector: labels.SelectorFromSet(labels.Set{"app": "test"})},
+			pod:  podWithoutLabel,
+			want: false,
+		},
+		{
+			name: "no selector or matcher",
+			vpa:  &Vpa{},
+			pod:  podWithLabel,
+			want: false,
+		},
+		{
+			name: "PodMatcher takes precedence over PodSelector",
+			vpa: &Vpa{
+				PodSelector: labels.Nothing(),
+				PodMatcher:  &fakePodMatcher{matches: true},
+			},
+			pod:  podWithoutLabel,
+			want: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, tc.vpa.MatchesPod(tc.pod))
+		})
+	}
+}
+
+var _ target.PodMatcher = &fakePodMatcher{}
@@ -0,0 +1,30 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package target resolves the set of pods governed by a given VPA object.
+package target
+
+import (
+	"k8s.io/apimachinery/pkg/labels"
+	vpa_types "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1beta2"
+)
+
+// VpaTargetSelectorFetcher gets a labelSelector used to gather Pods controlled by the given VPA.
+type VpaTargetSelectorFetcher interface {
+	// Fetch returns a label selector used to gather Pods controlled by the given VPA.
+	// If the VPA does not specify a target this returns nil, nil.
+	Fetch(vpa *vpa_types.VerticalPodAutoscaler) (labels.Selector, error)
+}
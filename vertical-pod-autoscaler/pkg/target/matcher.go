@@ -0,0 +1,45 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package target
+
+import (
+	v1 "k8s.io/api/core/v1"
+)
+
+// PodMatcher decides whether an individual pod is tracked by a VPA, for
+// tracking modes that can't be expressed as a single label.Selector (e.g.
+// matching on a pod annotation rather than its labels).
+type PodMatcher interface {
+	// Matches returns true if pod is tracked by the VPA this matcher was
+	// built for.
+	Matches(pod *v1.Pod) bool
+}
+
+// AnnotationPodMatcher matches pods that carry the given annotation key set
+// to the given value.
+type AnnotationPodMatcher struct {
+	AnnotationKey   string
+	AnnotationValue string
+}
+
+// Matches implements PodMatcher.
+func (m *AnnotationPodMatcher) Matches(pod *v1.Pod) bool {
+	if pod == nil {
+		return false
+	}
+	return pod.Annotations[m.AnnotationKey] == m.AnnotationValue
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package target
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestAnnotationPodMatcherMatches(t *testing.T) {
+	matcher := &AnnotationPodMatcher{
+		AnnotationKey:   "autoscaling.k8s.io/vpa-name",
+		AnnotationValue: "my-vpa",
+	}
+
+	testCases := []struct {
+		name string
+		pod  *v1.Pod
+		want bool
+	}{
+		{
+			name: "matching annotation",
+			pod: &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{"autoscaling.k8s.io/vpa-name": "my-vpa"},
+				},
+			},
+			want: true,
+		},
+		{
+			name: "different vpa name",
+			pod: &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{"autoscaling.k8s.io/vpa-name": "other-vpa"},
+				},
+			},
+			want: false,
+		},
+		{
+			name: "no annotations",
+			pod:  &v1.Pod{},
+			want: false,
+		},
+		{
+			name: "nil pod",
+			pod:  nil,
+			want: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, matcher.Matches(tc.pod))
+		})
+	}
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package target_mock is a mock of the target package, generated by mockgen.
+package target_mock
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	labels "k8s.io/apimachinery/pkg/labels"
+	vpa_types "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1beta2"
+)
+
+// MockVpaTargetSelectorFetcher is a mock of the VpaTargetSelectorFetcher interface.
+type MockVpaTargetSelectorFetcher struct {
+	ctrl     *gomock.Controller
+	recorder *MockVpaTargetSelectorFetcherMockRecorder
+}
+
+// MockVpaTargetSelectorFetcherMockRecorder is the mock recorder for MockVpaTargetSelectorFetcher.
+type MockVpaTargetSelectorFetcherMockRecorder struct {
+	mock *MockVpaTargetSelectorFetcher
+}
+
+// NewMockVpaTargetSelectorFetcher creates a new mock instance.
+func NewMockVpaTargetSelectorFetcher(ctrl *gomock.Controller) *MockVpaTargetSelectorFetcher {
+	mock := &MockVpaTargetSelectorFetcher{ctrl: ctrl}
+	mock.recorder = &MockVpaTargetSelectorFetcherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockVpaTargetSelectorFetcher) EXPECT() *MockVpaTargetSelectorFetcherMockRecorder {
+	return m.recorder
+}
+
+// Fetch mocks base method.
+func (m *MockVpaTargetSelectorFetcher) Fetch(vpa *vpa_types.VerticalPodAutoscaler) (labels.Selector, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Fetch", vpa)
+	ret0, _ := ret[0].(labels.Selector)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Fetch indicates an expected call of Fetch.
+func (mr *MockVpaTargetSelectorFetcherMockRecorder) Fetch(vpa interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Fetch", reflect.TypeOf((*MockVpaTargetSelectorFetcher)(nil).Fetch), vpa)
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package test holds shared builders and mocks used across the
+// recommender's unit tests.
+package test
+
+import (
+	"github.com/stretchr/testify/mock"
+
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	vpa_types "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1beta2"
+)
+
+// VerticalPodAutoscalerBuilder helps building test instances of VerticalPodAutoscaler.
+type VerticalPodAutoscalerBuilder interface {
+	WithName(vpaName string) VerticalPodAutoscalerBuilder
+	WithNamespace(namespace string) VerticalPodAutoscalerBuilder
+	WithContainer(containerName string) VerticalPodAutoscalerBuilder
+	WithTargetRef(targetRef *autoscalingv1.CrossVersionObjectReference) VerticalPodAutoscalerBuilder
+	Get() *vpa_types.VerticalPodAutoscaler
+}
+
+// VerticalPodAutoscaler returns a new VerticalPodAutoscalerBuilder.
+func VerticalPodAutoscaler() VerticalPodAutoscalerBuilder {
+	return &verticalPodAutoscalerBuilder{}
+}
+
+type verticalPodAutoscalerBuilder struct {
+	vpaName    string
+	namespace  string
+	containers []string
+	targetRef  *autoscalingv1.CrossVersionObjectReference
+}
+
+func (b *verticalPodAutoscalerBuilder) WithName(vpaName string) VerticalPodAutoscalerBuilder {
+	c := *b
+	c.vpaName = vpaName
+	return &c
+}
+
+func (b *verticalPodAutoscalerBuilder) WithNamespace(namespace string) VerticalPodAutoscalerBuilder {
+	c := *b
+	c.namespace = namespace
+	return &c
+}
+
+func (b *verticalPodAutoscalerBuilder) WithContainer(containerName string) VerticalPodAutoscalerBuilder {
+	c := *b
+	c.containers = append(c.containers, containerName)
+	return &c
+}
+
+func (b *verticalPodAutoscalerBuilder) WithTargetRef(targetRef *autoscalingv1.CrossVersionObjectReference) VerticalPodAutoscalerBuilder {
+	c := *b
+	c.targetRef = targetRef
+	return &c
+}
+
+func (b *verticalPodAutoscalerBuilder) Get() *vpa_types.VerticalPodAutoscaler {
+	return &vpa_types.VerticalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      b.vpaName,
+			Namespace: b.namespace,
+		},
+		Spec: vpa_types.VerticalPodAutoscalerSpec{
+			TargetRef: b.targetRef,
+		},
+	}
+}
+
+// VerticalPodAutoscalerListerMock is a testify mock for the VPA lister used by the recommender.
+type VerticalPodAutoscalerListerMock struct {
+	mock.Mock
+}
+
+// List mocks the VPA lister's List method.
+func (m *VerticalPodAutoscalerListerMock) List() ([]*vpa_types.VerticalPodAutoscaler, error) {
+	args := m.Called()
+	var result []*vpa_types.VerticalPodAutoscaler
+	if args.Get(0) != nil {
+		result = args.Get(0).([]*vpa_types.VerticalPodAutoscaler)
+	}
+	return result, args.Error(1)
+}